@@ -0,0 +1,88 @@
+package twiauth
+
+import (
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tg123/go-htpasswd"
+	"github.com/twipi/twikit/twipi"
+)
+
+// htpasswdAuth authenticates against a standard Apache htpasswd file,
+// supporting bcrypt, SHA, and MD5 crypt password hashes. If the DSN's reload
+// parameter is a positive duration, the file is re-read from disk on that
+// interval so that changes take effect without a restart; otherwise it is
+// read only once, when the backend is opened.
+type htpasswdAuth struct {
+	path string
+
+	mu   sync.RWMutex
+	file *htpasswd.File
+}
+
+func newHtpasswd(u *url.URL) (twipi.Auth, error) {
+	path := u.Opaque
+	if path == "" {
+		path = u.Host + u.Path
+	}
+	if path == "" {
+		return nil, errors.New("htpasswd auth requires a file path, e.g. htpasswd:///etc/twid/htpasswd")
+	}
+
+	auth := &htpasswdAuth{path: path}
+	if err := auth.reload(); err != nil {
+		return nil, err
+	}
+
+	if reload := u.Query().Get("reload"); reload != "" {
+		interval, err := time.ParseDuration(reload)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid reload duration")
+		}
+		// A non-positive interval (e.g. "0s") means "don't reload", not "poll
+		// as fast as possible": time.NewTicker panics on it, which would
+		// otherwise take the whole process down.
+		if interval > 0 {
+			go auth.watch(interval)
+		}
+	}
+
+	return auth, nil
+}
+
+func (a *htpasswdAuth) reload() error {
+	file, err := htpasswd.New(a.path, htpasswd.DefaultSystems, func(err error) {
+		slog.Warn("ignoring malformed htpasswd line", "path", a.path, "error", err)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to parse htpasswd file")
+	}
+
+	a.mu.Lock()
+	a.file = file
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *htpasswdAuth) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := a.reload(); err != nil {
+			slog.Error("failed to reload htpasswd file", "path", a.path, "error", err)
+		}
+	}
+}
+
+// Authenticate implements twipi.Auth.
+func (a *htpasswdAuth) Authenticate(username, password string) bool {
+	a.mu.RLock()
+	file := a.file
+	a.mu.RUnlock()
+
+	return file.Match(username, password)
+}
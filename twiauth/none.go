@@ -0,0 +1,8 @@
+package twiauth
+
+// noneAuth accepts every request. It is meant as an explicit opt-out for
+// local development, not something a production deployment should use.
+type noneAuth struct{}
+
+// Authenticate implements twipi.Auth.
+func (noneAuth) Authenticate(username, password string) bool { return true }
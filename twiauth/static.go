@@ -0,0 +1,32 @@
+package twiauth
+
+import (
+	"crypto/subtle"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/twipi/twikit/twipi"
+)
+
+// staticAuth authenticates a single hardcoded username/password pair.
+type staticAuth struct {
+	username, password string
+}
+
+func newStatic(u *url.URL) (twipi.Auth, error) {
+	q := u.Query()
+	username := q.Get("username")
+	password := q.Get("password")
+
+	if username == "" || password == "" {
+		return nil, errors.New("static auth requires username and password query parameters")
+	}
+
+	return staticAuth{username, password}, nil
+}
+
+// Authenticate implements twipi.Auth.
+func (a staticAuth) Authenticate(username, password string) bool {
+	return subtle.ConstantTimeCompare([]byte(username), []byte(a.username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(a.password)) == 1
+}
@@ -0,0 +1,40 @@
+// Package twiauth provides twipi.Auth implementations selected by a URL
+// scheme, so that an Auth backend can be configured as a single DSN string,
+// similarly to how database/sql drivers are selected.
+//
+// Supported schemes:
+//
+//   - static://?username=X&password=Y authenticates a single hardcoded
+//     credential.
+//   - htpasswd://path/to/file?reload=5s authenticates against an Apache
+//     htpasswd file (bcrypt, SHA, or MD5 crypt), reloading it from disk on
+//     the given interval.
+//   - none:// accepts every request; useful for local development.
+package twiauth
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/twipi/twikit/twipi"
+)
+
+// Open parses dsn as a URL and constructs the twipi.Auth backend named by
+// its scheme.
+func Open(dsn string) (twipi.Auth, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse auth DSN")
+	}
+
+	switch u.Scheme {
+	case "static":
+		return newStatic(u)
+	case "htpasswd":
+		return newHtpasswd(u)
+	case "none":
+		return noneAuth{}, nil
+	default:
+		return nil, errors.Errorf("twiauth: unknown scheme %q", u.Scheme)
+	}
+}
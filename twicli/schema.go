@@ -0,0 +1,242 @@
+package twicli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Arg describes a single named positional argument in a Schema.
+type Arg struct {
+	// Name identifies the argument and is used to look it up on Args and to
+	// render it in generated usage text.
+	Name string
+	// Required marks the argument as mandatory. Do returns an error before
+	// calling Action if a required argument is missing. Optional arguments
+	// must come after all required ones.
+	Required bool
+}
+
+// Flag describes a named flag in a Schema, given on the command line as
+// "--name value" or "--name=value", or, if Bool is true, as the bare switch
+// "--name".
+type Flag struct {
+	// Name is the flag's name, without the leading "--".
+	Name string
+	// Bool marks the flag as a boolean switch that takes no value unless
+	// explicitly given with "=", e.g. "--verbose" or "--verbose=false".
+	Bool bool
+	// Required marks the flag as mandatory. Do returns an error before
+	// calling Action if a required flag is missing.
+	Required bool
+}
+
+// Schema declares the positional arguments and flags a Command's Action
+// expects. If a Command has a Schema, Do tokenizes the text remaining after
+// Prefix matches using shell-style quoting and parses it into a structured
+// Args value attached to Message, instead of leaving it as a raw Body
+// string.
+type Schema struct {
+	// Args lists the named positional arguments, in the order they must
+	// appear.
+	Args []Arg
+	// Flags lists the flags accepted anywhere among the positional
+	// arguments.
+	Flags []Flag
+	// Variadic allows any positional arguments beyond those listed in Args
+	// to be captured instead of raising an error. They're accessible via
+	// Args.Tail.
+	Variadic bool
+}
+
+// Args holds the values parsed from a message body according to a Schema.
+// The zero value is an empty Args, as seen by a Command without a Schema.
+type Args struct {
+	positional map[string]string
+	flags      map[string]string
+	bools      map[string]bool
+	tail       []string
+}
+
+// String returns the value of the named positional argument, or "" if it
+// wasn't given.
+func (a Args) String(name string) string {
+	return a.positional[name]
+}
+
+// Flag returns the value of the named flag and whether it was given.
+func (a Args) Flag(name string) (string, bool) {
+	v, ok := a.flags[name]
+	return v, ok
+}
+
+// FlagOr returns the value of the named flag, or fallback if it wasn't
+// given.
+func (a Args) FlagOr(name, fallback string) string {
+	if v, ok := a.flags[name]; ok {
+		return v
+	}
+	return fallback
+}
+
+// Bool returns whether the named boolean flag was given and true.
+func (a Args) Bool(name string) bool {
+	return a.bools[name]
+}
+
+// Tail returns the positional arguments left over past those declared in
+// Schema.Args, in order. It is only populated for schemas with Variadic
+// set.
+func (a Args) Tail() []string {
+	return a.tail
+}
+
+// parse tokenizes body with shell-style quoting and parses the result
+// according to the schema.
+func (s Schema) parse(body string) (Args, error) {
+	tokens, err := tokenize(body)
+	if err != nil {
+		return Args{}, errors.Wrap(err, "cannot tokenize arguments")
+	}
+
+	flagsByName := make(map[string]Flag, len(s.Flags))
+	for _, f := range s.Flags {
+		flagsByName[f.Name] = f
+	}
+
+	args := Args{
+		positional: make(map[string]string, len(s.Args)),
+		flags:      make(map[string]string),
+		bools:      make(map[string]bool),
+	}
+
+	var positionals []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		name, ok := strings.CutPrefix(token, "--")
+		if !ok {
+			positionals = append(positionals, token)
+			continue
+		}
+
+		value, hasValue := "", false
+		if eq := strings.IndexByte(name, '='); eq >= 0 {
+			name, value, hasValue = name[:eq], name[eq+1:], true
+		}
+
+		flag, ok := flagsByName[name]
+		if !ok {
+			return Args{}, errors.Errorf("unknown flag --%s", name)
+		}
+
+		if flag.Bool {
+			if !hasValue {
+				value = "true"
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return Args{}, errors.Wrapf(err, "invalid value for --%s", name)
+			}
+			args.bools[name] = b
+			continue
+		}
+
+		if !hasValue {
+			i++
+			if i >= len(tokens) {
+				return Args{}, errors.Errorf("flag --%s requires a value", name)
+			}
+			value = tokens[i]
+		}
+		args.flags[name] = value
+	}
+
+	for i, arg := range s.Args {
+		if i < len(positionals) {
+			args.positional[arg.Name] = positionals[i]
+		} else if arg.Required {
+			return Args{}, errors.Errorf("missing required argument %q", arg.Name)
+		}
+	}
+
+	if len(positionals) > len(s.Args) {
+		if !s.Variadic {
+			return Args{}, errors.Errorf("too many arguments (expected %d)", len(s.Args))
+		}
+		args.tail = positionals[len(s.Args):]
+	}
+
+	for _, flag := range s.Flags {
+		if !flag.Required {
+			continue
+		}
+		if flag.Bool {
+			if _, ok := args.bools[flag.Name]; !ok {
+				return Args{}, errors.Errorf("missing required flag --%s", flag.Name)
+			}
+		} else if _, ok := args.flags[flag.Name]; !ok {
+			return Args{}, errors.Errorf("missing required flag --%s", flag.Name)
+		}
+	}
+
+	return args, nil
+}
+
+// tokenize splits s into shell-like tokens, honoring single and double
+// quotes (and backslash escapes within double quotes) so that arguments
+// such as `post "hello world" --channel 123` split into ["post", "hello
+// world", "--channel", "123"].
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	var inToken bool
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case quote != 0:
+			switch {
+			case c == quote:
+				quote = 0
+			case c == '\\' && quote == '"' && i+1 < len(s):
+				i++
+				cur.WriteByte(s[i])
+			default:
+				cur.WriteByte(c)
+			}
+
+		case c == '\'' || c == '"':
+			quote = c
+			inToken = true
+
+		case c == ' ' || c == '\t':
+			if inToken {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+				inToken = false
+			}
+
+		case c == '\\' && i+1 < len(s):
+			i++
+			cur.WriteByte(s[i])
+			inToken = true
+
+		default:
+			cur.WriteByte(c)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inToken {
+		tokens = append(tokens, cur.String())
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,135 @@
+package twicli
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`post hello --channel 123`, []string{"post", "hello", "--channel", "123"}},
+		{`post "hello world" --channel=123`, []string{"post", "hello world", "--channel=123"}},
+		{`post 'hello world'`, []string{"post", "hello world"}},
+		{`post "say \"hi\""`, []string{"post", `say "hi"`}},
+		{`  post   hello  `, []string{"post", "hello"}},
+	}
+
+	for _, tt := range tests {
+		got, err := tokenize(tt.in)
+		if err != nil {
+			t.Fatalf("tokenize(%q) error = %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenize(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTokenizeUnterminatedQuote(t *testing.T) {
+	if _, err := tokenize(`post "hello`); err == nil {
+		t.Fatal("tokenize() = nil error, want unterminated quote error")
+	}
+}
+
+func TestSchemaParse(t *testing.T) {
+	schema := Schema{
+		Args: []Arg{
+			{Name: "text", Required: true},
+		},
+		Flags: []Flag{
+			{Name: "channel", Required: true},
+			{Name: "urgent", Bool: true},
+		},
+	}
+
+	args, err := schema.parse(`"hello world" --channel 123 --urgent`)
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if got := args.String("text"); got != "hello world" {
+		t.Errorf("String(text) = %q, want %q", got, "hello world")
+	}
+	if got := args.FlagOr("channel", ""); got != "123" {
+		t.Errorf("FlagOr(channel) = %q, want %q", got, "123")
+	}
+	if !args.Bool("urgent") {
+		t.Error("Bool(urgent) = false, want true")
+	}
+}
+
+func TestSchemaParseMissingRequiredArg(t *testing.T) {
+	schema := Schema{Args: []Arg{{Name: "text", Required: true}}}
+	if _, err := schema.parse(""); err == nil {
+		t.Fatal("parse() = nil error, want missing argument error")
+	}
+}
+
+func TestSchemaParseMissingRequiredFlag(t *testing.T) {
+	schema := Schema{Flags: []Flag{{Name: "channel", Required: true}}}
+	if _, err := schema.parse(""); err == nil {
+		t.Fatal("parse() = nil error, want missing flag error")
+	}
+}
+
+func TestSchemaParseVariadicTail(t *testing.T) {
+	schema := Schema{
+		Args:     []Arg{{Name: "first", Required: true}},
+		Variadic: true,
+	}
+
+	args, err := schema.parse("one two three")
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if got := args.String("first"); got != "one" {
+		t.Errorf("String(first) = %q, want %q", got, "one")
+	}
+	if want := []string{"two", "three"}; !reflect.DeepEqual(args.Tail(), want) {
+		t.Errorf("Tail() = %#v, want %#v", args.Tail(), want)
+	}
+}
+
+func TestSchemaParseTooManyArgs(t *testing.T) {
+	schema := Schema{Args: []Arg{{Name: "first", Required: true}}}
+	if _, err := schema.parse("one two"); err == nil {
+		t.Fatal("parse() = nil error, want too many arguments error")
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	cmd := Command{
+		Name:    "post",
+		Summary: "post a message to a channel",
+		Schema: &Schema{
+			Args:  []Arg{{Name: "text", Required: true}},
+			Flags: []Flag{{Name: "channel", Required: true}},
+		},
+	}
+
+	want := `post <text> --channel <value> — post a message to a channel`
+	if got := cmd.Usage(); got != want {
+		t.Errorf("Usage() = %q, want %q", got, want)
+	}
+}
+
+func TestNewHelpCommand(t *testing.T) {
+	cmds := []Command{
+		{Name: "post", Summary: "post a message"},
+		{Name: "list", Summary: "list channels"},
+	}
+	help := NewHelpCommand(cmds)
+
+	err := help.Do(context.Background(), Message{Body: "help"})
+	if err == nil {
+		t.Fatal("Do() = nil error, want help text carried as error")
+	}
+
+	want := "post — post a message\nlist — list channels"
+	if got := ErrorMessage(err); got != want {
+		t.Errorf("ErrorMessage() = %q, want %q", got, want)
+	}
+}
@@ -95,6 +95,9 @@ func CombinePrefixes(prefixes ...PrefixFunc) PrefixFunc {
 type Message struct {
 	twipi.Message
 	Body string
+	// Args holds the arguments parsed from Body according to the matched
+	// Command's Schema. It is the zero Args value for commands without one.
+	Args Args
 }
 
 // ActionFunc is the type of the function called by a Command.
@@ -103,8 +106,20 @@ type ActionFunc func(ctx context.Context, msg Message) error
 // Command is a command-line interface that can be used to parse
 // command-line-like messages from users and dispatch them to handlers.
 type Command struct {
-	Prefix PrefixFunc
-	Action ActionFunc
+	// Name identifies the command in generated usage text (see Usage and
+	// NewHelpCommand). It has no effect on matching; Prefix is still what
+	// decides whether the command activates.
+	Name string
+	// Summary is a one-line description of the command, shown after its
+	// usage in generated help text.
+	Summary string
+	Prefix  PrefixFunc
+	Action  ActionFunc
+	// Schema, if set, causes Do to parse the text remaining after Prefix
+	// matches into a structured Args value attached to Message, instead of
+	// leaving it as a raw Body string. Do returns an error without calling
+	// Action if the text doesn't satisfy the schema.
+	Schema *Schema
 }
 
 // ErrNotMatched is returned by Command.Do if the command does not match the
@@ -154,19 +169,106 @@ func (c *Command) Loop(ctx context.Context, h *twipi.MessageHandler, cli *twipi.
 }
 
 // Do runs the command. ErrNotMatched is returned if the command does not match
-// the given message.
+// the given message. If the command has a Schema, the text remaining after
+// Prefix matches is parsed into msg.Args before Action is called; a message
+// that doesn't satisfy the schema is reported as an error rather than
+// ErrNotMatched, since the command itself did match.
 func (c *Command) Do(ctx context.Context, msg Message) error {
-	if body, ok := c.Prefix(msg.Body); ok {
-		msg.Body = body
-		return c.Action(ctx, msg)
+	body, ok := c.Prefix(msg.Body)
+	if !ok {
+		return ErrNotMatched
+	}
+	msg.Body = body
+
+	if c.Schema != nil {
+		args, err := c.Schema.parse(body)
+		if err != nil {
+			return errors.Wrapf(err, "usage: %s", c.Usage())
+		}
+		msg.Args = args
 	}
-	return ErrNotMatched
+
+	return c.Action(ctx, msg)
 }
 
+// Usage returns a one-line, SMS-friendly usage string for the command, e.g.
+// "post <text> [--channel <value>] — post a message to a channel". Commands
+// without a Name, Schema, or Summary render as fewer parts accordingly.
+func (c *Command) Usage() string {
+	var b strings.Builder
+	b.WriteString(c.Name)
+
+	if c.Schema != nil {
+		for _, arg := range c.Schema.Args {
+			b.WriteByte(' ')
+			if arg.Required {
+				b.WriteString("<" + arg.Name + ">")
+			} else {
+				b.WriteString("[" + arg.Name + "]")
+			}
+		}
+		if c.Schema.Variadic {
+			b.WriteString(" [...]")
+		}
+		for _, flag := range c.Schema.Flags {
+			usage := "--" + flag.Name
+			if !flag.Bool {
+				usage += " <value>"
+			}
+			if !flag.Required {
+				usage = "[" + usage + "]"
+			}
+			b.WriteByte(' ')
+			b.WriteString(usage)
+		}
+	}
+
+	if c.Summary != "" {
+		b.WriteString(" — ")
+		b.WriteString(c.Summary)
+	}
+
+	return b.String()
+}
+
+// NewHelpCommand returns a Command matching the word "help" that replies
+// with the Usage of every command in cmds, one per line. It's meant to be
+// included alongside cmds in a call to Subcommands so users can discover
+// what a command tree supports over SMS.
+func NewHelpCommand(cmds []Command) Command {
+	return Command{
+		Name:   "help",
+		Prefix: NewWordPrefix("help", false),
+		Action: func(ctx context.Context, msg Message) error {
+			return &helpReply{RenderHelp(cmds)}
+		},
+	}
+}
+
+// RenderHelp renders the Usage of every command in cmds as SMS-friendly help
+// text, one command per line.
+func RenderHelp(cmds []Command) string {
+	lines := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		lines = append(lines, cmd.Usage())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// helpReply carries rendered help text back through DoAndReply. It
+// implements error so it can be returned from an ActionFunc, but
+// ErrorMessage recognizes it and renders its text verbatim instead of
+// treating it as a failure.
+type helpReply struct {
+	text string
+}
+
+func (h *helpReply) Error() string { return h.text }
+
 // DoAndReply runs the command and replies to the given message with the
 // returned error. If the error is nil, no reply is sent.
 func (c *Command) DoAndReply(ctx context.Context, cli *twipi.Client, msg twipi.Message) {
-	if err := c.Do(ctx, Message{msg, msg.Body}); err != nil {
+	if err := c.Do(ctx, Message{Message: msg, Body: msg.Body}); err != nil {
 		errBody := ErrorMessage(err)
 		if err := cli.ReplySMS(ctx, msg, errBody); err != nil {
 			logger := slogctx.From(ctx)
@@ -183,11 +285,15 @@ func (c *Command) DoAndReply(ctx context.Context, cli *twipi.Client, msg twipi.M
 // ErrorMessage is a helper function that returns a new message body from the
 // given error.
 func ErrorMessage(err error) string {
+	var help *helpReply
+
 	switch {
 	case err == nil:
 		return ""
 	case errors.Is(err, ErrNotMatched):
 		return "Sorry! I'm not sure what you mean."
+	case errors.As(err, &help):
+		return help.text
 	default:
 		return "Sorry, an error occured: " + err.Error()
 	}
@@ -0,0 +1,41 @@
+package twid
+
+import (
+	"context"
+
+	"github.com/twipi/twikit/twisms"
+)
+
+// Provider is a twisms backend that can be loaded by the twid server,
+// analogous to Module but for twisms.MessageService backends such as
+// Twilio, a test/loopback provider, or memsms.
+type Provider struct {
+	// Name is the name of the provider. It is used as its config block name
+	// and as the key for enabling it.
+	Name string
+	// New is the constructor that creates a new ProviderHandler.
+	New func() ProviderHandler
+}
+
+// ProviderHandler is a provider instance created by a registered Provider.
+type ProviderHandler interface {
+	// Config returns the provider's configuration. The configuration is
+	// assumed to be the root structure, and each provider should wrap its
+	// configuration in a block named after the provider.
+	Config() any
+	// Matcher returns the twisms.Matcher that selects this provider for
+	// outbound sends.
+	Matcher() twisms.Matcher
+	// Service returns the provider's MessageService.
+	Service() twisms.MessageService
+	// Start starts the provider, e.g. connecting to its upstream API.
+	Start(ctx context.Context) error
+}
+
+var providers = []Provider{}
+
+// RegisterProvider registers a twisms provider to be loaded by the twid
+// server.
+func RegisterProvider(p Provider) {
+	providers = append(providers, p)
+}
@@ -11,20 +11,27 @@ import (
 	"flag"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/diamondburned/listener"
 	"github.com/go-chi/chi/v5"
 	"github.com/pkg/errors"
 	"github.com/twipi/twikit/internal/cfgutil"
+	"github.com/twipi/twikit/twiauth"
 	"github.com/twipi/twikit/twicli"
 	"github.com/twipi/twikit/twipi"
+	"github.com/twipi/twikit/twisms"
+	"github.com/twipi/twikit/twisms/smsgw"
+	"github.com/twipi/twikit/twisms/twismsrpc"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 	"libdb.so/ctxt"
 )
 
@@ -32,9 +39,28 @@ import (
 type Config struct {
 	HTTP struct {
 		ListenAddr cfgutil.EnvString `toml:"listen_addr" json:"listen_addr"`
+		// Auth is a twiauth DSN, e.g. "htpasswd:///etc/twid/htpasswd?reload=5s",
+		// protecting every module-exposed HTTP route mounted by HTTPCommander.
+		// Twilio webhooks mounted through twipi are unaffected; they're
+		// protected by signature verification instead. Empty disables auth.
+		Auth cfgutil.EnvString `toml:"auth" json:"auth"`
 	} `toml:"http" json:"http"`
 }
 
+// TwismsrpcConfig is the twismsrpc config block.
+type TwismsrpcConfig struct {
+	// Auth is a twiauth DSN protecting the twismsrpc endpoints. Empty
+	// disables auth.
+	Auth cfgutil.EnvString `toml:"auth" json:"auth"`
+}
+
+// SmsgwConfig is the smsgw config block.
+type SmsgwConfig struct {
+	// ListenAddr is the address that the gRPC gateway listens on, e.g.
+	// ":9090".
+	ListenAddr cfgutil.EnvString `toml:"listen_addr" json:"listen_addr"`
+}
+
 // ConfigType is the type of the configuration file, e.g. "toml" or "json".
 type ConfigType string
 
@@ -86,6 +112,14 @@ type TwipiHandler interface {
 	BindTwipi(*twipi.ConfiguredServer)
 }
 
+// TwismsHandler is a module that can expose a twisms.MessageService, e.g. so
+// that it can be shared with other twid instances over twismsrpc.
+type TwismsHandler interface {
+	Handler
+	// TwismsService returns the module's MessageService.
+	TwismsService() twisms.MessageService
+}
+
 // CommandHandler is a module that uses the twicli.Command API.
 type CommandHandler interface {
 	Handler
@@ -117,28 +151,42 @@ type HTTPCommander interface {
 // starting them.
 type Loader struct {
 	Config struct {
-		Twid  Config       `toml:"twid" json:"twid"`
-		Twipi twipi.Config `toml:"twipi" json:"twipi"`
+		Twid      Config          `toml:"twid" json:"twid"`
+		Twipi     twipi.Config    `toml:"twipi" json:"twipi"`
+		Twismsrpc TwismsrpcConfig `toml:"twismsrpc" json:"twismsrpc"`
+		Smsgw     SmsgwConfig     `toml:"smsgw" json:"smsgw"`
 	}
 
-	handlers map[string]Handler
-	enabled  map[string]bool
+	handlers         map[string]Handler
+	providerHandlers map[string]ProviderHandler
+	enabled          map[string]bool
 
-	mux   *chi.Mux
-	http  *http.Server
-	twipi *twipi.ConfiguredServer
+	mux    *chi.Mux
+	http   *http.Server
+	twipi  *twipi.ConfiguredServer
+	smsgw  *smsgw.Gateway
+	grpc   *grpc.Server
+	twisms *twisms.Router
 }
 
-// NewLoader creates a new loader with the given modules.
+// NewLoader creates a new loader with the given modules. Registered
+// providers (see RegisterProvider) are always available, since -- unlike
+// modules -- there is no per-test need to scope them.
 func NewLoader(modules []Module) *Loader {
 	handlers := make(map[string]Handler, len(modules))
 	for _, module := range modules {
 		handlers[module.Name] = module.New()
 	}
 
+	providerHandlers := make(map[string]ProviderHandler, len(providers))
+	for _, provider := range providers {
+		providerHandlers[provider.Name] = provider.New()
+	}
+
 	return &Loader{
-		handlers: handlers,
-		enabled:  make(map[string]bool, len(modules)),
+		handlers:         handlers,
+		providerHandlers: providerHandlers,
+		enabled:          make(map[string]bool, len(modules)+len(providers)),
 	}
 }
 
@@ -169,6 +217,67 @@ func Main() {
 	}
 }
 
+// twismsService builds (or returns the already-built) twisms.Router
+// composing every enabled Provider, plus -- for modules that predate the
+// Provider mechanism -- every enabled module implementing TwismsHandler as a
+// catch-all provider. It returns false if nothing is configured to back it,
+// and an error if the configuration is ambiguous (more than one catch-all
+// TwismsHandler module enabled, whose relative order would otherwise be
+// nondeterministic across restarts).
+func (l *Loader) twismsService() (twisms.MessageService, bool, error) {
+	if l.twisms != nil {
+		return l.twisms, true, nil
+	}
+
+	router := twisms.NewRouter()
+	found := false
+
+	providerNames := make([]string, 0, len(l.providerHandlers))
+	for name := range l.providerHandlers {
+		providerNames = append(providerNames, name)
+	}
+	sort.Strings(providerNames)
+
+	for _, name := range providerNames {
+		if !l.enabled[name] {
+			continue
+		}
+		handler := l.providerHandlers[name]
+		router.Register(handler.Matcher(), handler.Service())
+		found = true
+	}
+
+	handlerNames := make([]string, 0, len(l.handlers))
+	for name := range l.handlers {
+		handlerNames = append(handlerNames, name)
+	}
+	sort.Strings(handlerNames)
+
+	var catchAllNames []string
+	for _, name := range handlerNames {
+		if !l.enabled[name] {
+			continue
+		}
+		if twismsHandler, ok := l.handlers[name].(TwismsHandler); ok {
+			catchAllNames = append(catchAllNames, name)
+			router.Register(twisms.MatchAny(), twismsHandler.TwismsService())
+			found = true
+		}
+	}
+	if len(catchAllNames) > 1 {
+		return nil, false, errors.Errorf(
+			"ambiguous twisms routing: modules %s all register as catch-all providers",
+			strings.Join(catchAllNames, ", "))
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	l.twisms = router
+	return router, true, nil
+}
+
 // LoadConfigFile loads the configuration file from the given path into all the
 // module handlers.
 func (l *Loader) LoadConfigFile(path string) error {
@@ -197,6 +306,11 @@ func (l *Loader) LoadConfig(b []byte, configType string) error {
 			configs = append(configs, config)
 		}
 	}
+	for _, handler := range l.providerHandlers {
+		if config := handler.Config(); config != nil {
+			configs = append(configs, config)
+		}
+	}
 
 	if err := cfgutil.ParseMany(b, configType, configs...); err != nil {
 		return errors.Wrap(err, "failed to parse config")
@@ -212,6 +326,11 @@ func (l *Loader) LoadConfig(b []byte, configType string) error {
 			return errors.Wrap(err, "failed to create twipi server")
 		}
 
+		// Must be called before any webhook routes are registered so that
+		// it applies to all of them, including the ones mounted below by
+		// TwipiHandler modules and twismsrpc.
+		twipisrv.VerifyTwilioSignature(l.Config.Twipi.Twilio.AuthToken)
+
 		l.twipi = twipisrv
 
 		for name, handler := range l.handlers {
@@ -224,6 +343,47 @@ func (l *Loader) LoadConfig(b []byte, configType string) error {
 		}
 	}
 
+	if l.enabled["twismsrpc"] {
+		// twismsrpc rides on the webhook mount that twipi provides; without
+		// it there's nowhere to serve the endpoint from.
+		if l.twipi == nil {
+			return errors.New("twismsrpc is enabled but twipi is not, so it has no webhook mount to serve from")
+		}
+
+		service, ok, err := l.twismsService()
+		if err != nil {
+			return errors.Wrap(err, "twismsrpc")
+		}
+		if !ok {
+			return errors.New("twismsrpc is enabled but no enabled provider or TwismsHandler module is configured")
+		}
+
+		var webhook twipi.WebhookRegisterer = twismsrpc.NewServer(service)
+		if dsn := l.Config.Twismsrpc.Auth.Value(); dsn != "" {
+			auth, err := twiauth.Open(dsn)
+			if err != nil {
+				return errors.Wrap(err, "failed to open twismsrpc auth")
+			}
+			webhook = twipi.AuthenticatedWebhook{WebhookRegisterer: webhook, Auth: auth, Realm: "twismsrpc"}
+		}
+
+		l.twipi.RegisterWebhook(webhook)
+	}
+
+	if l.enabled["smsgw"] {
+		service, ok, err := l.twismsService()
+		if err != nil {
+			return errors.Wrap(err, "smsgw")
+		}
+		if !ok {
+			return errors.New("smsgw is enabled but no enabled provider or TwismsHandler module is configured")
+		}
+
+		l.smsgw = smsgw.NewGateway(service)
+		l.grpc = grpc.NewServer()
+		smsgw.RegisterMessageGatewayServer(l.grpc, l.smsgw)
+	}
+
 	if l.Config.Twid.HTTP.ListenAddr != "" {
 		l.mux = chi.NewMux()
 		l.http = &http.Server{
@@ -231,13 +391,30 @@ func (l *Loader) LoadConfig(b []byte, configType string) error {
 			Handler: l.mux,
 		}
 
+		var auth twipi.Auth
+		if dsn := l.Config.Twid.HTTP.Auth.Value(); dsn != "" {
+			a, err := twiauth.Open(dsn)
+			if err != nil {
+				return errors.Wrap(err, "failed to open HTTP auth")
+			}
+			auth = a
+		}
+
 		for name, handler := range l.handlers {
 			if !l.enabled[name] {
 				continue
 			}
 
 			if httpHandler, ok := handler.(HTTPCommander); ok {
-				l.mux.Handle(path.Join(httpHandler.HTTPPrefix(), "*"), httpHandler.HTTPHandler())
+				prefix := path.Join(httpHandler.HTTPPrefix(), "*")
+				if auth != nil {
+					l.mux.Group(func(gr chi.Router) {
+						gr.Use(twipi.AuthMiddleware(auth, name))
+						gr.Handle(prefix, httpHandler.HTTPHandler())
+					})
+				} else {
+					l.mux.Handle(prefix, httpHandler.HTTPHandler())
+				}
 			}
 		}
 	}
@@ -268,6 +445,31 @@ func (l *Loader) Start(ctx context.Context) error {
 		defer l.twipi.Close()
 	}
 
+	if l.twisms != nil {
+		errg.Go(func() error {
+			return l.twisms.Run(ctx)
+		})
+	}
+
+	for name, handler := range l.providerHandlers {
+		if !l.enabled[name] {
+			continue
+		}
+
+		name := name
+		handler := handler
+
+		errg.Go(func() error {
+			logger.Info("starting provider", "provider", name)
+			defer logger.Info("provider stopped", "provider", name)
+
+			if err := handler.Start(ctx); err != nil {
+				return errors.Wrapf(err, "failed to start provider %q", name)
+			}
+			return nil
+		})
+	}
+
 	if l.http != nil {
 		errg.Go(func() error {
 			logger.InfoContext(ctx,
@@ -279,6 +481,31 @@ func (l *Loader) Start(ctx context.Context) error {
 		})
 	}
 
+	if l.grpc != nil {
+		lis, err := net.Listen("tcp", l.Config.Smsgw.ListenAddr.Value())
+		if err != nil {
+			return errors.Wrap(err, "failed to listen for smsgw")
+		}
+
+		errg.Go(func() error {
+			return l.smsgw.Run(ctx)
+		})
+
+		errg.Go(func() error {
+			logger.InfoContext(ctx,
+				"starting smsgw gRPC server",
+				"addr", lis.Addr())
+			defer logger.Info("smsgw gRPC server stopped")
+
+			go func() {
+				<-ctx.Done()
+				l.grpc.GracefulStop()
+			}()
+
+			return l.grpc.Serve(lis)
+		})
+	}
+
 	for name, handler := range l.handlers {
 		logger := logger.With("module", name)
 		ctx := ctxt.With(ctx, logger)
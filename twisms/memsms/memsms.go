@@ -0,0 +1,80 @@
+// Package memsms provides an in-memory twisms.MessageService, useful for
+// unit testing CommandHandler modules and other twisms consumers without
+// spinning up a real provider like Twilio.
+package memsms
+
+import (
+	"context"
+	"sync"
+
+	"github.com/twipi/twikit/twisms"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+)
+
+// Service is an in-memory twisms.MessageService. Messages sent through
+// SendMessage are recorded and can be inspected with Sent; messages can be
+// injected as if received from the network with Receive.
+type Service struct {
+	mu   sync.Mutex
+	sent []*twismsproto.Message
+
+	subMu       sync.Mutex
+	subscribers map[chan<- *twismsproto.Message]*twismsproto.MessageFilters
+}
+
+var _ twisms.MessageService = (*Service)(nil)
+
+// New creates a new, empty Service.
+func New() *Service {
+	return &Service{
+		subscribers: make(map[chan<- *twismsproto.Message]*twismsproto.MessageFilters),
+	}
+}
+
+// SendMessage implements twisms.MessageSender. It records msg; it never
+// fails.
+func (s *Service) SendMessage(ctx context.Context, msg *twismsproto.Message) error {
+	s.mu.Lock()
+	s.sent = append(s.sent, msg)
+	s.mu.Unlock()
+	return nil
+}
+
+// Sent returns every message passed to SendMessage so far, in order.
+func (s *Service) Sent() []*twismsproto.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*twismsproto.Message(nil), s.sent...)
+}
+
+// Receive delivers msg to every subscriber whose filters match, as if it had
+// been received from the network. Delivery to a subscriber whose channel is
+// not ready to receive is dropped rather than blocking the caller.
+func (s *Service) Receive(msg *twismsproto.Message) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for ch, filters := range s.subscribers {
+		if !twisms.FilterMessage(filters, msg) {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// SubscribeMessages implements twisms.MessageSubscriber.
+func (s *Service) SubscribeMessages(ch chan<- *twismsproto.Message, filters *twismsproto.MessageFilters) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	s.subscribers[ch] = filters
+}
+
+// UnsubscribeMessages implements twisms.MessageSubscriber.
+func (s *Service) UnsubscribeMessages(ch chan<- *twismsproto.Message) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	delete(s.subscribers, ch)
+}
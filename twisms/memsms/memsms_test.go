@@ -0,0 +1,55 @@
+package memsms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/twipi/twipi/proto/out/twismsproto"
+)
+
+func TestService_SendMessage(t *testing.T) {
+	svc := New()
+
+	msg := &twismsproto.Message{From: "+15550000001", To: "+15550000002"}
+	if err := svc.SendMessage(context.Background(), msg); err != nil {
+		t.Fatalf("SendMessage() = %v, want nil", err)
+	}
+
+	sent := svc.Sent()
+	if len(sent) != 1 || sent[0] != msg {
+		t.Fatalf("Sent() = %v, want [%v]", sent, msg)
+	}
+}
+
+func TestService_ReceiveMatchesFilters(t *testing.T) {
+	svc := New()
+
+	ch := make(chan *twismsproto.Message, 1)
+	svc.SubscribeMessages(ch, &twismsproto.MessageFilters{
+		Filters: []*twismsproto.MessageFilter{
+			{Filter: &twismsproto.MessageFilter_MatchTo{MatchTo: "+15550000002"}},
+		},
+	})
+	defer svc.UnsubscribeMessages(ch)
+
+	matching := &twismsproto.Message{From: "+15550000001", To: "+15550000002"}
+	svc.Receive(matching)
+
+	select {
+	case got := <-ch:
+		if got != matching {
+			t.Fatalf("received %v, want %v", got, matching)
+		}
+	default:
+		t.Fatal("expected matching message to be delivered")
+	}
+
+	nonMatching := &twismsproto.Message{From: "+15550000001", To: "+15550000003"}
+	svc.Receive(nonMatching)
+
+	select {
+	case got := <-ch:
+		t.Fatalf("received unexpected message %v", got)
+	default:
+	}
+}
@@ -0,0 +1,125 @@
+package twisms
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+)
+
+// ErrNoProvider is returned by Router.SendMessage when no registered
+// provider's Matcher accepts the message's From number.
+var ErrNoProvider = errors.New("twisms: no provider registered for this From number")
+
+// Matcher decides whether a provider should handle outbound messages for the
+// given From number.
+type Matcher func(from string) bool
+
+// MatchFrom returns a Matcher that matches an exact From number.
+func MatchFrom(from string) Matcher {
+	return func(candidate string) bool { return candidate == from }
+}
+
+// MatchAny returns a Matcher that matches every From number. It is useful as
+// a catch-all provider, or when only one provider is registered.
+func MatchAny() Matcher {
+	return func(string) bool { return true }
+}
+
+type routerProvider struct {
+	matcher Matcher
+	service MessageService
+}
+
+// Router composes multiple MessageService providers (e.g. Twilio, a
+// test/loopback provider, a future Vonage or Telnyx provider) into a single
+// MessageService. Outbound sends are routed to the first registered
+// provider whose Matcher accepts the message's From number; inbound
+// subscriptions are fanned out across every registered provider.
+//
+// Register must be called before Run. Router is safe for concurrent use
+// once Run has started.
+type Router struct {
+	providers []routerProvider
+
+	subMu       sync.Mutex
+	subscribers map[chan<- *twismsproto.Message]*twismsproto.MessageFilters
+}
+
+var _ MessageService = (*Router)(nil)
+
+// NewRouter creates an empty Router.
+func NewRouter() *Router {
+	return &Router{
+		subscribers: make(map[chan<- *twismsproto.Message]*twismsproto.MessageFilters),
+	}
+}
+
+// Register adds a provider to the router, consulted for outbound sends in
+// the order providers were registered. It must be called before Run.
+func (r *Router) Register(matcher Matcher, service MessageService) {
+	r.providers = append(r.providers, routerProvider{matcher, service})
+}
+
+// Run subscribes to every registered provider and fans their messages out to
+// the router's own subscribers until ctx is canceled.
+func (r *Router) Run(ctx context.Context) error {
+	ch := make(chan *twismsproto.Message)
+
+	for _, p := range r.providers {
+		p.service.SubscribeMessages(ch, nil)
+		defer p.service.UnsubscribeMessages(ch)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-ch:
+			r.broadcast(msg)
+		}
+	}
+}
+
+func (r *Router) broadcast(msg *twismsproto.Message) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	for ch, filters := range r.subscribers {
+		if !FilterMessage(filters, msg) {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+			// The subscriber isn't keeping up; drop this message for it
+			// rather than block the whole router.
+		}
+	}
+}
+
+// SendMessage implements MessageSender. It routes msg to the first
+// registered provider whose Matcher accepts msg.From.
+func (r *Router) SendMessage(ctx context.Context, msg *twismsproto.Message) error {
+	for _, p := range r.providers {
+		if p.matcher(msg.GetFrom()) {
+			return p.service.SendMessage(ctx, msg)
+		}
+	}
+	return ErrNoProvider
+}
+
+// SubscribeMessages implements MessageSubscriber.
+func (r *Router) SubscribeMessages(ch chan<- *twismsproto.Message, filters *twismsproto.MessageFilters) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+	r.subscribers[ch] = filters
+}
+
+// UnsubscribeMessages implements MessageSubscriber.
+func (r *Router) UnsubscribeMessages(ch chan<- *twismsproto.Message) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+	delete(r.subscribers, ch)
+}
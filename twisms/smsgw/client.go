@@ -0,0 +1,88 @@
+package smsgw
+
+import (
+	"context"
+	"sync"
+
+	"github.com/twipi/twikit/twisms"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Client implements twisms.MessageService against a remote Gateway over
+// gRPC.
+type Client struct {
+	cc *grpc.ClientConn
+
+	mu     sync.Mutex
+	cancel map[chan<- *twismsproto.Message]context.CancelFunc
+}
+
+var _ twisms.MessageService = (*Client)(nil)
+
+// NewClient wraps cc, an already-dialed connection to a Gateway, as a
+// twisms.MessageService.
+func NewClient(cc *grpc.ClientConn) *Client {
+	return &Client{
+		cc:     cc,
+		cancel: make(map[chan<- *twismsproto.Message]context.CancelFunc),
+	}
+}
+
+// SendMessage implements twisms.MessageSender.
+func (c *Client) SendMessage(ctx context.Context, msg *twismsproto.Message) error {
+	out := new(emptypb.Empty)
+	return c.cc.Invoke(ctx, "/"+ServiceName+"/Send", msg, out)
+}
+
+// SubscribeMessages implements twisms.MessageSubscriber. Messages are
+// delivered to ch until the subscription's context is canceled or
+// UnsubscribeMessages is called; delivery happens in its own goroutine.
+func (c *Client) SubscribeMessages(ch chan<- *twismsproto.Message, filters *twismsproto.MessageFilters) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c.mu.Lock()
+	c.cancel[ch] = cancel
+	c.mu.Unlock()
+
+	go c.streamSubscription(ctx, ch, filters)
+}
+
+// UnsubscribeMessages implements twisms.MessageSubscriber.
+func (c *Client) UnsubscribeMessages(ch chan<- *twismsproto.Message) {
+	c.mu.Lock()
+	cancel, ok := c.cancel[ch]
+	delete(c.cancel, ch)
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+func (c *Client) streamSubscription(ctx context.Context, ch chan<- *twismsproto.Message, filters *twismsproto.MessageFilters) {
+	stream, err := c.cc.NewStream(ctx, &ServiceDesc.Streams[0], "/"+ServiceName+"/Subscribe")
+	if err != nil {
+		return
+	}
+	if err := stream.SendMsg(filters); err != nil {
+		return
+	}
+	if err := stream.CloseSend(); err != nil {
+		return
+	}
+
+	for {
+		msg := new(twismsproto.Message)
+		if err := stream.RecvMsg(msg); err != nil {
+			return
+		}
+
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package smsgw
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/twipi/twikit/twisms"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// ErrSubscriberTooSlow is returned to a Subscribe stream that is terminated
+// because it could not keep up with its bounded queue.
+var ErrSubscriberTooSlow = errors.New("smsgw: subscriber too slow, disconnected")
+
+// Gateway implements MessageGatewayServer by fanning out messages from an
+// in-process twisms.MessageSubscriber to any number of connected gRPC
+// streams, and forwarding unary Send calls to a twisms.MessageSender.
+type Gateway struct {
+	service twisms.MessageService
+
+	ch chan *twismsproto.Message
+
+	mu          sync.Mutex
+	subscribers map[chan *twismsproto.Message]*twismsproto.MessageFilters
+}
+
+var _ MessageGatewayServer = (*Gateway)(nil)
+
+// NewGateway creates a new Gateway backed by service. Run must be called to
+// start fanning out messages.
+func NewGateway(service twisms.MessageService) *Gateway {
+	return &Gateway{
+		service:     service,
+		ch:          make(chan *twismsproto.Message),
+		subscribers: make(map[chan *twismsproto.Message]*twismsproto.MessageFilters),
+	}
+}
+
+// Run subscribes to the underlying service and fans out messages to
+// connected subscribers until ctx is canceled.
+func (g *Gateway) Run(ctx context.Context) error {
+	g.service.SubscribeMessages(g.ch, nil)
+	defer g.service.UnsubscribeMessages(g.ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-g.ch:
+			g.broadcast(msg)
+		}
+	}
+}
+
+func (g *Gateway) broadcast(msg *twismsproto.Message) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for ch, filters := range g.subscribers {
+		if !twisms.FilterMessage(filters, msg) {
+			continue
+		}
+
+		select {
+		case ch <- msg:
+		default:
+			// The subscriber's bounded queue is full; drop it rather than
+			// block the fan-out loop or grow memory unboundedly.
+			close(ch)
+			delete(g.subscribers, ch)
+		}
+	}
+}
+
+// Send implements MessageGatewayServer.
+func (g *Gateway) Send(ctx context.Context, msg *twismsproto.Message) (*emptypb.Empty, error) {
+	if err := g.service.SendMessage(ctx, msg); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Subscribe implements MessageGatewayServer.
+func (g *Gateway) Subscribe(filters *twismsproto.MessageFilters, stream MessageGateway_SubscribeServer) error {
+	ch := make(chan *twismsproto.Message, SubscriberQueue)
+
+	g.mu.Lock()
+	g.subscribers[ch] = filters
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.subscribers, ch)
+		g.mu.Unlock()
+	}()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return ErrSubscriberTooSlow
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Package smsgw exposes a twisms.MessageService over gRPC, using a
+// long-lived server-streaming RPC for inbound messages instead of twismsrpc's
+// chunked HTTP stream. It lets a single process holding an SMS provider
+// connection (e.g. Twilio) share that connection with any number of
+// downstream consumers -- auth code routers, bots, pagers -- each
+// subscribing with its own twismsproto.MessageFilters, without each one
+// owning the provider's webhook.
+//
+// The RPC plumbing in service.go is written by hand in the same shape that
+// protoc-gen-go-grpc would generate, since the smsgw.proto service
+// definition has not been added to the twismsproto module yet.
+package smsgw
+
+// ServiceName is the fully-qualified gRPC service name that Gateway serves
+// and Client dials.
+const ServiceName = "twisms.smsgw.MessageGateway"
+
+// SubscriberQueue is the number of messages buffered per connected
+// subscriber before it is considered slow and dropped.
+const SubscriberQueue = 64
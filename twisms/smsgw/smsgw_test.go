@@ -0,0 +1,74 @@
+package smsgw
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/twipi/twikit/twisms/memsms"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestClientServerRoundTrip(t *testing.T) {
+	svc := memsms.New()
+	gw := NewGateway(svc)
+
+	lis := bufconn.Listen(1024 * 1024)
+	grpcSrv := grpc.NewServer()
+	RegisterMessageGatewayServer(grpcSrv, gw)
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go gw.Run(ctx)
+
+	cc, err := grpc.DialContext(ctx, "passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.DialContext() = %v, want nil", err)
+	}
+	defer cc.Close()
+
+	client := NewClient(cc)
+
+	ch := make(chan *twismsproto.Message, 1)
+	client.SubscribeMessages(ch, nil)
+	defer client.UnsubscribeMessages(ch)
+
+	want := &twismsproto.Message{From: "+15550000001", To: "+15550000002"}
+
+	var got *twismsproto.Message
+	deadline := time.Now().Add(2 * time.Second)
+	for got == nil && time.Now().Before(deadline) {
+		svc.Receive(want)
+		select {
+		case got = <-ch:
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	if got == nil {
+		t.Fatal("timed out waiting for subscribed message")
+	}
+	if got.GetFrom() != want.From || got.GetTo() != want.To {
+		t.Fatalf("received %v, want %v", got, want)
+	}
+
+	send := &twismsproto.Message{From: "+15550000002", To: "+15550000001"}
+	if err := client.SendMessage(context.Background(), send); err != nil {
+		t.Fatalf("SendMessage() = %v, want nil", err)
+	}
+
+	sent := svc.Sent()
+	if len(sent) != 1 || sent[0].GetFrom() != send.From || sent[0].GetTo() != send.To {
+		t.Fatalf("Sent() = %v, want [%v]", sent, send)
+	}
+}
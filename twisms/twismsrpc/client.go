@@ -0,0 +1,170 @@
+package twismsrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/twipi/twikit/twisms"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Client implements twisms.MessageService against a remote twismsrpc.Server
+// over HTTP.
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	contentType string
+
+	mu     sync.Mutex
+	cancel map[chan<- *twismsproto.Message]context.CancelFunc
+}
+
+var _ twisms.MessageService = (*Client)(nil)
+
+// NewClient creates a new Client that talks to the twismsrpc server mounted
+// at baseURL, e.g. "http://localhost:8080". If useJSON is true, the client
+// uses the JSON fallback encoding instead of protobuf; this is primarily
+// useful for debugging a remote twid with curl.
+func NewClient(httpClient *http.Client, baseURL string, useJSON bool) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	contentType := protobufContentType
+	if useJSON {
+		contentType = jsonContentType
+	}
+
+	return &Client{
+		httpClient:  httpClient,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		contentType: contentType,
+		cancel:      make(map[chan<- *twismsproto.Message]context.CancelFunc),
+	}
+}
+
+// SendMessage implements twisms.MessageSender.
+func (c *Client) SendMessage(ctx context.Context, msg *twismsproto.Message) error {
+	_, err := c.call(ctx, "Send", msg, &twismsproto.Message{})
+	return err
+}
+
+// SubscribeMessages implements twisms.MessageSubscriber. The subscription is
+// backed by a long-lived HTTP request that is torn down once ch is passed to
+// UnsubscribeMessages.
+func (c *Client) SubscribeMessages(ch chan<- *twismsproto.Message, filters *twismsproto.MessageFilters) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c.mu.Lock()
+	c.cancel[ch] = cancel
+	c.mu.Unlock()
+
+	go c.streamSubscription(ctx, ch, filters)
+}
+
+// UnsubscribeMessages implements twisms.MessageSubscriber.
+func (c *Client) UnsubscribeMessages(ch chan<- *twismsproto.Message) {
+	c.mu.Lock()
+	cancel, ok := c.cancel[ch]
+	delete(c.cancel, ch)
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+func (c *Client) streamSubscription(ctx context.Context, ch chan<- *twismsproto.Message, filters *twismsproto.MessageFilters) {
+	body, err := c.encode(filters)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+PathPrefix+"/Subscribe", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", c.contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReaderSize(resp.Body, 64*1024)
+
+	for {
+		b, err := readFrame(reader, c.contentType)
+		if err != nil {
+			return
+		}
+
+		msg := new(twismsproto.Message)
+		if err := c.decode(b, msg); err != nil {
+			continue
+		}
+
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Client) call(ctx context.Context, method string, req, resp proto.Message) (proto.Message, error) {
+	body, err := c.encode(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+PathPrefix+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	httpReq.Header.Set("Content-Type", c.contentType)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to perform %s request", method)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("twismsrpc: %s: server returned %d: %s", method, httpResp.StatusCode, respBody)
+	}
+
+	if err := c.decode(respBody, resp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response body")
+	}
+
+	return resp, nil
+}
+
+func (c *Client) encode(msg proto.Message) ([]byte, error) {
+	if c.contentType == jsonContentType {
+		return protojson.Marshal(msg)
+	}
+	return proto.Marshal(msg)
+}
+
+func (c *Client) decode(b []byte, msg proto.Message) error {
+	if c.contentType == jsonContentType {
+		return protojson.Unmarshal(b, msg)
+	}
+	return proto.Unmarshal(b, msg)
+}
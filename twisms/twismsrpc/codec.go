@@ -0,0 +1,119 @@
+package twismsrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const jsonContentType = "application/json"
+const protobufContentType = "application/protobuf"
+
+// maxFrameSize bounds the length prefix read off a protobuf stream frame, so
+// a corrupt or malicious length doesn't cause an unbounded allocation.
+const maxFrameSize = 4 << 20 // 4 MiB
+
+// contentTypeOf returns the content type that a response to r should be
+// encoded with: JSON if the caller asked for it, protobuf otherwise.
+func contentTypeOf(r *http.Request) string {
+	if r.Header.Get("Content-Type") == jsonContentType {
+		return jsonContentType
+	}
+	return protobufContentType
+}
+
+func marshal(contentType string, msg proto.Message) ([]byte, error) {
+	if contentType == jsonContentType {
+		return protojson.Marshal(msg)
+	}
+	return proto.Marshal(msg)
+}
+
+func unmarshal(contentType string, b []byte, msg proto.Message) error {
+	if contentType == jsonContentType {
+		return protojson.Unmarshal(b, msg)
+	}
+	return proto.Unmarshal(b, msg)
+}
+
+func unmarshalBody(r *http.Request, msg proto.Message) error {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read request body")
+	}
+	return unmarshal(contentTypeOf(r), b, msg)
+}
+
+func marshalBody(w http.ResponseWriter, contentType string, msg proto.Message) error {
+	b, err := marshal(contentType, msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal response")
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// writeFrame writes msg to w as a single frame of a Subscribe stream.
+//
+// JSON frames are newline-delimited, since protojson never emits a raw
+// newline byte. Protobuf frames are length-prefixed instead: protobuf's
+// binary wire format routinely contains 0x0A bytes (e.g. the tag byte for
+// any field-1 length-delimited field), so newline-delimiting would corrupt
+// or silently drop real messages.
+func writeFrame(w io.Writer, contentType string, msg proto.Message) error {
+	b, err := marshal(contentType, msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+
+	if contentType == jsonContentType {
+		_, err := w.Write(append(b, '\n'))
+		return err
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(b)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// readFrame reads a single frame written by writeFrame from r.
+func readFrame(r *bufio.Reader, contentType string) ([]byte, error) {
+	if contentType == jsonContentType {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		return bytes.TrimRight(line, "\n"), nil
+	}
+
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+
+	n := binary.BigEndian.Uint32(length[:])
+	if n > maxFrameSize {
+		return nil, errors.Errorf("twismsrpc: frame of %d bytes exceeds maximum of %d", n, maxFrameSize)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	http.Error(w, err.Error(), code)
+}
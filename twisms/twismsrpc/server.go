@@ -0,0 +1,90 @@
+package twismsrpc
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"github.com/twipi/twikit/twisms"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+)
+
+// subscriberQueue is the number of messages buffered per streaming
+// subscriber before messages start being dropped for a slow client.
+const subscriberQueue = 32
+
+// Server adapts an in-process twisms.MessageService to the twismsrpc HTTP
+// protocol. It implements twipi.WebhookRegisterer, so it can be mounted
+// directly onto a twipi.WebhookRouter alongside the other webhook handlers.
+type Server struct {
+	service twisms.MessageService
+}
+
+// NewServer wraps service so that it can be served over twismsrpc.
+func NewServer(service twisms.MessageService) *Server {
+	return &Server{service: service}
+}
+
+// Mount mounts the server's RPC methods onto r under PathPrefix. It
+// implements twipi.WebhookRegisterer.
+func (s *Server) Mount(r chi.Router) {
+	r.Post(PathPrefix+"/Send", s.handleSend)
+	r.Post(PathPrefix+"/Subscribe", s.handleSubscribe)
+}
+
+// Close implements io.Closer. It is a no-op: the server does not own the
+// lifecycle of the wrapped service.
+func (s *Server) Close() error { return nil }
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	var msg twismsproto.Message
+	if err := unmarshalBody(r, &msg); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.service.SendMessage(r.Context(), &msg); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	contentType := contentTypeOf(r)
+	w.Header().Set("Content-Type", contentType)
+	marshalBody(w, contentType, &twismsproto.Message{})
+}
+
+func (s *Server) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	var filters twismsproto.MessageFilters
+	if err := unmarshalBody(r, &filters); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming unsupported by response writer"))
+		return
+	}
+
+	ch := make(chan *twismsproto.Message, subscriberQueue)
+	s.service.SubscribeMessages(ch, &filters)
+	defer s.service.UnsubscribeMessages(ch)
+
+	contentType := contentTypeOf(r)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-ch:
+			if err := writeFrame(w, contentType, msg); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Package twismsrpc exposes a twisms.MessageService over HTTP using a
+// Twirp-flavored protocol, so a twid instance can run the SMS-provider side
+// of twisms in one process (e.g. the one holding the Twilio credentials) and
+// host command handlers in another.
+//
+// Unary calls (Send) follow standard Twirp routing and content negotiation:
+// POST /twirp/twismsproto.MessageService/<Method> with either
+// application/protobuf or application/json bodies, selected by the request's
+// Content-Type. Subscribe is not a unary call -- Twirp has no streaming
+// support -- so it is served as a chunked stream of twismsproto.Message
+// frames under the same path prefix, framed with a 4-byte length prefix for
+// application/protobuf and newline-delimited for the application/json
+// fallback (JSON never contains a raw newline byte, unlike protobuf's binary
+// wire format). This keeps routing and ad-hoc debugging (curl + jq against
+// the JSON fallback) consistent with the rest of the service.
+package twismsrpc
+
+// PathPrefix is the HTTP path prefix that Server mounts its RPC methods
+// under and that Client sends its requests to.
+const PathPrefix = "/twirp/twismsproto.MessageService"
@@ -0,0 +1,68 @@
+package twismsrpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/twipi/twikit/twisms/memsms"
+	"github.com/twipi/twipi/proto/out/twismsproto"
+)
+
+func TestClientServerRoundTrip(t *testing.T) {
+	for _, useJSON := range []bool{false, true} {
+		name := "protobuf"
+		if useJSON {
+			name = "json"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			svc := memsms.New()
+
+			mux := chi.NewMux()
+			NewServer(svc).Mount(mux)
+
+			httpSrv := httptest.NewServer(mux)
+			defer httpSrv.Close()
+
+			client := NewClient(httpSrv.Client(), httpSrv.URL, useJSON)
+
+			ch := make(chan *twismsproto.Message, 1)
+			client.SubscribeMessages(ch, nil)
+			defer client.UnsubscribeMessages(ch)
+
+			// A string field's wire encoding contains the byte 0x0A, which is
+			// also the newline byte a naive framing scheme would split on --
+			// this is what regresses if Subscribe framing breaks.
+			want := &twismsproto.Message{From: "+15550000001", To: "+15550000002"}
+
+			var got *twismsproto.Message
+			deadline := time.Now().Add(2 * time.Second)
+			for got == nil && time.Now().Before(deadline) {
+				svc.Receive(want)
+				select {
+				case got = <-ch:
+				case <-time.After(50 * time.Millisecond):
+				}
+			}
+			if got == nil {
+				t.Fatal("timed out waiting for subscribed message")
+			}
+			if got.GetFrom() != want.From || got.GetTo() != want.To {
+				t.Fatalf("received %v, want %v", got, want)
+			}
+
+			send := &twismsproto.Message{From: "+15550000002", To: "+15550000001"}
+			if err := client.SendMessage(context.Background(), send); err != nil {
+				t.Fatalf("SendMessage() = %v, want nil", err)
+			}
+
+			sent := svc.Sent()
+			if len(sent) != 1 || sent[0].GetFrom() != send.From || sent[0].GetTo() != send.To {
+				t.Fatalf("Sent() = %v, want [%v]", sent, send)
+			}
+		})
+	}
+}
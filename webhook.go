@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/twipi/twikit/internal/cfgutil"
 )
 
 // WebhookRegisterer is a type that can register a webhook handler into a
@@ -24,9 +25,35 @@ func NewWebhookRouter() *WebhookRouter {
 	return &WebhookRouter{Mux: *chi.NewMux()}
 }
 
-// RegisterWebhook registers a webhook handler into the server.
+// VerifyTwilioSignature installs middleware onto the router that verifies
+// the X-Twilio-Signature header of every request using authToken, rejecting
+// mismatches with a 403. It must be called before any routes are registered
+// directly on the router for it to apply to them.
+//
+// It only protects routes mounted directly on the router, i.e. the
+// Twilio-origin webhook intake (such as TwipiHandler.BindTwipi's routes).
+// Routes registered through RegisterWebhook are mounted on an isolated
+// sub-router and are unaffected, since those callers -- twismsrpc, for
+// instance -- are not necessarily Twilio and don't send a signature header;
+// they opt into AuthRequirer's Basic auth instead.
+func (r *WebhookRouter) VerifyTwilioSignature(authToken cfgutil.EnvString) {
+	r.Mux.Use(VerifyTwilioSignature(authToken))
+}
+
+// RegisterWebhook registers a webhook handler into the server. If registerer
+// implements AuthRequirer, its routes are protected with HTTP Basic auth per
+// its RequireAuth configuration. Routes are mounted on an isolated
+// sub-router, so they don't inherit middleware -- such as
+// VerifyTwilioSignature -- installed on the top-level router.
 func (r *WebhookRouter) RegisterWebhook(registerer WebhookRegisterer) {
-	r.Mux.Group(registerer.Mount)
+	sub := chi.NewRouter()
+	if authRequirer, ok := registerer.(AuthRequirer); ok {
+		auth, realm := authRequirer.RequireAuth()
+		sub.Use(AuthMiddleware(auth, realm))
+	}
+	registerer.Mount(sub)
+
+	r.Mux.Mount("/", sub)
 	r.closers = append(r.closers, registerer)
 }
 
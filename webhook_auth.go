@@ -0,0 +1,54 @@
+package twipi
+
+import "net/http"
+
+// Auth authenticates the username and password pair from an incoming
+// request's HTTP Basic auth header. It is used to protect non-Twilio-origin
+// routes mounted onto a WebhookRouter, e.g. Twirp/gRPC-gateway endpoints,
+// admin handlers, or module-exposed HTTP under HTTPCommander.
+type Auth interface {
+	// Authenticate reports whether username and password are valid.
+	Authenticate(username, password string) bool
+}
+
+// AuthRequirer is implemented by a WebhookRegisterer that wants the routes
+// it mounts protected by HTTP Basic auth. WebhookRouter checks for this
+// interface in RegisterWebhook, so individual registerers can opt in (or
+// require a different realm) independently of one another.
+type AuthRequirer interface {
+	WebhookRegisterer
+	// RequireAuth returns the Auth backend to authenticate against and the
+	// realm to advertise in the WWW-Authenticate challenge.
+	RequireAuth() (auth Auth, realm string)
+}
+
+// AuthenticatedWebhook wraps a WebhookRegisterer to require HTTP Basic auth
+// against Auth, implementing AuthRequirer. It is useful for protecting a
+// registerer that doesn't otherwise know about Auth, e.g. one provided by a
+// third-party module.
+type AuthenticatedWebhook struct {
+	WebhookRegisterer
+	Auth  Auth
+	Realm string
+}
+
+// RequireAuth implements AuthRequirer.
+func (w AuthenticatedWebhook) RequireAuth() (Auth, string) {
+	return w.Auth, w.Realm
+}
+
+// AuthMiddleware returns an HTTP Basic auth middleware backed by auth,
+// challenging with the given realm on failure.
+func AuthMiddleware(auth Auth, realm string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok || !auth.Authenticate(username, password) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
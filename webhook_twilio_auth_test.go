@@ -0,0 +1,134 @@
+package twipi
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// These are Twilio's own published example vectors for validating request
+// signatures. See https://www.twilio.com/docs/usage/security#test-credentials.
+func TestCheckTwilioSignature_PublishedVector(t *testing.T) {
+	const authToken = "12345"
+	const signature = "RSOYDt4T1cUTdK1PDd93/VVr8B8="
+	const rawURL = "https://mycompany.com/myapp.php?foo=1&bar=2"
+
+	form := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+14158675310"},
+		"Digits":  {"1234"},
+		"From":    {"+14158675310"},
+		"To":      {"+18005551212"},
+	}
+
+	req := newSignedRequest(t, rawURL, form, signature)
+
+	if err := checkTwilioSignature(authToken, req); err != nil {
+		t.Fatalf("checkTwilioSignature() = %v, want nil", err)
+	}
+}
+
+func TestCheckTwilioSignature_RejectsTamperedParams(t *testing.T) {
+	const authToken = "12345"
+	const signature = "RSOYDt4T1cUTdK1PDd93/VVr8B8="
+	const rawURL = "https://mycompany.com/myapp.php?foo=1&bar=2"
+
+	form := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+14158675310"},
+		"Digits":  {"9999"}, // tampered
+		"From":    {"+14158675310"},
+		"To":      {"+18005551212"},
+	}
+
+	req := newSignedRequest(t, rawURL, form, signature)
+
+	if err := checkTwilioSignature(authToken, req); err == nil {
+		t.Fatal("checkTwilioSignature() = nil, want error for tampered params")
+	}
+}
+
+func TestCheckTwilioSignature_RejectsMissingHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "https://mycompany.com/myapp.php", nil)
+
+	if err := checkTwilioSignature("12345", req); err != errMissingSignature {
+		t.Fatalf("checkTwilioSignature() = %v, want errMissingSignature", err)
+	}
+}
+
+func TestCheckTwilioSignature_JSONWithBodySHA256(t *testing.T) {
+	const authToken = "s3cr3t"
+	const body = `{"hello":"world"}`
+
+	sum := sha256.Sum256([]byte(body))
+	bodySHA256 := hex.EncodeToString(sum[:])
+
+	rawURL := "https://mycompany.com/myapp.json?bodySHA256=" + bodySHA256
+
+	signature := signURL(authToken, rawURL)
+
+	req := httptest.NewRequest(http.MethodPost, rawURL, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(twilioSignatureHeader, signature)
+
+	if err := checkTwilioSignature(authToken, req); err != nil {
+		t.Fatalf("checkTwilioSignature() = %v, want nil", err)
+	}
+}
+
+func TestCheckTwilioSignature_JSONRejectsTamperedBody(t *testing.T) {
+	const authToken = "s3cr3t"
+	const body = `{"hello":"world"}`
+
+	sum := sha256.Sum256([]byte(body))
+	bodySHA256 := hex.EncodeToString(sum[:])
+
+	rawURL := "https://mycompany.com/myapp.json?bodySHA256=" + bodySHA256
+	signature := signURL(authToken, rawURL)
+
+	req := httptest.NewRequest(http.MethodPost, rawURL, strings.NewReader(`{"hello":"tampered"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(twilioSignatureHeader, signature)
+
+	if err := checkTwilioSignature(authToken, req); err != errBodyHashMismatch {
+		t.Fatalf("checkTwilioSignature() = %v, want errBodyHashMismatch", err)
+	}
+}
+
+func TestVerifyTwilioSignature_EmptyTokenDisablesCheck(t *testing.T) {
+	mw := VerifyTwilioSignature("")
+
+	called := false
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "https://mycompany.com/myapp.php", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Fatal("handler was not called with an empty auth token")
+	}
+}
+
+func newSignedRequest(t *testing.T, rawURL string, form url.Values, signature string) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, rawURL, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(twilioSignatureHeader, signature)
+	return req
+}
+
+func signURL(authToken, rawURL string) string {
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(rawURL))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}